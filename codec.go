@@ -0,0 +1,159 @@
+package bolt
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec transforms values before they are written to bbolt and back
+// again after they are read, e.g. to compress or encrypt them.
+type Codec interface {
+	Encode([]byte) ([]byte, error)
+	Decode([]byte) ([]byte, error)
+}
+
+// WithCodec makes the storage run every value written to bucketQueue
+// and bucketCookies through c.Encode, and every value read back through
+// c.Decode. It is useful when serialized requests are large (headers,
+// bodies) or when persisted cookies should not sit on disk in
+// plaintext.
+func WithCodec(c Codec) Option {
+	return func(s *Storage) error {
+		s.codec = c
+		return nil
+	}
+}
+
+// Chain returns a Codec that encodes by applying codecs in order and
+// decodes by applying them in reverse, e.g. Chain(zstdCodec, aesCodec)
+// compresses then encrypts on write, and decrypts then decompresses on
+// read.
+func Chain(codecs ...Codec) Codec {
+	return chainCodec{codecs: codecs}
+}
+
+type chainCodec struct {
+	codecs []Codec
+}
+
+func (c chainCodec) Encode(v []byte) ([]byte, error) {
+	var err error
+	for _, codec := range c.codecs {
+		v, err = codec.Encode(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (c chainCodec) Decode(v []byte) ([]byte, error) {
+	var err error
+	for i := len(c.codecs) - 1; i >= 0; i-- {
+		v, err = c.codecs[i].Decode(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// GzipCodec compresses values with gzip.
+type GzipCodec struct{}
+
+// NewGzipCodec returns a Codec that compresses values with gzip.
+func NewGzipCodec() Codec {
+	return GzipCodec{}
+}
+
+func (GzipCodec) Encode(v []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(v); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GzipCodec) Decode(v []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(v))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// ZstdCodec compresses values with zstd.
+type ZstdCodec struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// NewZstdCodec returns a Codec that compresses values with zstd.
+func NewZstdCodec() (Codec, error) {
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ZstdCodec{encoder: enc, decoder: dec}, nil
+}
+
+func (c *ZstdCodec) Encode(v []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(v, nil), nil
+}
+
+func (c *ZstdCodec) Decode(v []byte) ([]byte, error) {
+	return c.decoder.DecodeAll(v, nil)
+}
+
+// AESGCMCodec encrypts values with AES-GCM.
+type AESGCMCodec struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMCodec returns a Codec that encrypts values with AES-GCM
+// using key, which must be 16, 24 or 32 bytes to select AES-128,
+// AES-192 or AES-256.
+func NewAESGCMCodec(key []byte) (Codec, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCMCodec{aead: aead}, nil
+}
+
+func (c *AESGCMCodec) Encode(v []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, v, nil), nil
+}
+
+func (c *AESGCMCodec) Decode(v []byte) ([]byte, error) {
+	n := c.aead.NonceSize()
+	if len(v) < n {
+		return nil, fmt.Errorf("bolt: encrypted value shorter than nonce")
+	}
+	nonce, ciphertext := v[:n], v[n:]
+	return c.aead.Open(nil, nonce, ciphertext, nil)
+}
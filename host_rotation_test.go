@@ -0,0 +1,20 @@
+package bolt
+
+import "testing"
+
+func TestNextHostIndex(t *testing.T) {
+	hosts := []string{"a.example", "b.example", "c.example"}
+
+	if got := nextHostIndex(hosts, nil); got != 0 {
+		t.Errorf("nil cursor: got %d, want 0", got)
+	}
+	if got := nextHostIndex(hosts, []byte("b.example")); got != 1 {
+		t.Errorf("cursor %q: got %d, want 1", "b.example", got)
+	}
+	// A cursor recorded for a host that has since been drained and
+	// removed (e.g. a single-entry shard) is not in hosts, so the
+	// rotation restarts from index 0.
+	if got := nextHostIndex(hosts, []byte("gone.example")); got != 0 {
+		t.Errorf("missing cursor: got %d, want 0", got)
+	}
+}
@@ -0,0 +1,79 @@
+package bolt
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+// TestQueueKeyPriorityOrdering checks that queueKey sorts strictly by
+// priority, highest first (so byte order is *descending* in
+// priority), including at and beyond the int32 boundary where a naive
+// MaxInt32-priority computation wraps.
+func TestQueueKeyPriorityOrdering(t *testing.T) {
+	priorities := []int{
+		math.MinInt32 - 1, // clamps to math.MinInt32
+		math.MinInt32,
+		-1000,
+		-1,
+		0,
+		1,
+		1000,
+		math.MaxInt32,
+		math.MaxInt32 + 1, // clamps to math.MaxInt32
+	}
+	var keys [][]byte
+	for _, p := range priorities {
+		keys = append(keys, queueKey(p, time.Time{}, 0))
+	}
+	for i := 1; i < len(keys); i++ {
+		if bytes.Compare(keys[i-1], keys[i]) < 0 {
+			t.Errorf("priority %d sorted before %d: keys not monotonically descending", priorities[i-1], priorities[i])
+		}
+	}
+	// The two out-of-range priorities must clamp to the same key as
+	// their in-range boundary, not wrap around to the opposite end.
+	if !bytes.Equal(keys[0], keys[1]) {
+		t.Errorf("priority below int32 range did not clamp to math.MinInt32's key")
+	}
+	if !bytes.Equal(keys[len(keys)-1], keys[len(keys)-2]) {
+		t.Errorf("priority above int32 range did not clamp to math.MaxInt32's key")
+	}
+}
+
+// TestQueueKeyNotBeforeOrdering checks that, for equal priority,
+// earlier notBefore times sort first.
+func TestQueueKeyNotBeforeOrdering(t *testing.T) {
+	now := time.Now()
+	earlier := queueKey(0, now, 0)
+	later := queueKey(0, now.Add(time.Hour), 0)
+	never := queueKey(0, time.Time{}, 0)
+
+	if bytes.Compare(earlier, later) >= 0 {
+		t.Errorf("earlier notBefore did not sort before later notBefore")
+	}
+	if bytes.Compare(never, earlier) >= 0 {
+		t.Errorf("zero notBefore (no delay) did not sort before a non-zero notBefore")
+	}
+}
+
+// TestQueueKeySequenceOrdering checks that, for equal priority and
+// notBefore, lower sequence numbers sort first (insertion order).
+func TestQueueKeySequenceOrdering(t *testing.T) {
+	first := queueKey(0, time.Time{}, 1)
+	second := queueKey(0, time.Time{}, 2)
+	if bytes.Compare(first, second) >= 0 {
+		t.Errorf("lower sequence did not sort before higher sequence")
+	}
+}
+
+func TestQueueKeyNotBeforeRoundTrip(t *testing.T) {
+	if got := queueKeyNotBefore(queueKey(0, time.Time{}, 0)); !got.IsZero() {
+		t.Errorf("zero notBefore round-tripped to %v, want zero Time", got)
+	}
+	want := time.Unix(0, time.Now().UnixNano())
+	if got := queueKeyNotBefore(queueKey(0, want, 0)); !got.Equal(want) {
+		t.Errorf("notBefore round-tripped to %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package bolt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplaceFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+
+	if err := os.WriteFile(src, []byte("snapshot"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := replaceFile(src, dst, 0o644); err != nil {
+		t.Fatalf("replaceFile: %v", err)
+	}
+
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "snapshot" {
+		t.Errorf("dst = %q, want %q", got, "snapshot")
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Errorf("src still exists after replaceFile, err = %v", err)
+	}
+}
+
+func TestReplaceFileMissingSrc(t *testing.T) {
+	dir := t.TempDir()
+	if err := replaceFile(filepath.Join(dir, "missing"), filepath.Join(dir, "dst"), 0o644); err == nil {
+		t.Fatal("expected an error for a missing source file, got nil")
+	}
+}
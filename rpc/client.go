@@ -0,0 +1,353 @@
+package rpc
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	bolt "github.com/felix/colly-bolt-storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// Client implements the same colly.Storage and colly.Queue interfaces
+// as bolt.Storage, but talks to a Server over a pooled gRPC connection
+// instead of opening the bbolt file locally. Several Clients dialed at
+// the same Server can therefore share one persistent queue, visited
+// set and cookie jar.
+type Client struct {
+	pool          *connPool
+	debug         bolt.Logger
+	flushInterval time.Duration
+	batchSize     int
+
+	mu                sync.Mutex
+	pendingVisited    []uint64
+	pendingVisitedSet map[uint64]struct{}
+	pendingRequests   [][]byte
+
+	stopFlush chan struct{}
+	flushDone chan struct{}
+}
+
+// clientConfig collects the settings ClientOptions apply, before the
+// connection pool (which needs min/max up front) is created.
+type clientConfig struct {
+	minConns            int
+	maxConns            int
+	flushInterval       time.Duration
+	batchSize           int
+	healthCheckInterval time.Duration
+	debug               bolt.Logger
+	dialOpts            []grpc.DialOption
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*clientConfig)
+
+// MinConns sets the number of connections NewClient dials eagerly. It
+// defaults to 1. If it exceeds MaxConns, it is clamped down to it.
+func MinConns(n int) ClientOption {
+	return func(cfg *clientConfig) { cfg.minConns = n }
+}
+
+// MaxConns caps the number of connections the Client will open; get
+// blocks for an idle connection once the cap is reached. It defaults
+// to 4 and is clamped up to 1.
+func MaxConns(n int) ClientOption {
+	return func(cfg *clientConfig) { cfg.maxConns = n }
+}
+
+// FlushInterval sets how often buffered Visited/AddRequest calls are
+// flushed to the Server, in addition to being flushed early once
+// FlushBatchSize is reached. It defaults to 200ms.
+func FlushInterval(d time.Duration) ClientOption {
+	return func(cfg *clientConfig) { cfg.flushInterval = d }
+}
+
+// FlushBatchSize sets how many buffered Visited/AddRequest calls
+// trigger an early flush. It defaults to 64.
+func FlushBatchSize(n int) ClientOption {
+	return func(cfg *clientConfig) { cfg.batchSize = n }
+}
+
+// HealthCheckInterval sets how often idle pooled connections are
+// pinged and, if unresponsive, replaced. It defaults to 30s.
+func HealthCheckInterval(d time.Duration) ClientOption {
+	return func(cfg *clientConfig) { cfg.healthCheckInterval = d }
+}
+
+// Debug sets a Logger for the client.
+func Debug(l bolt.Logger) ClientOption {
+	return func(cfg *clientConfig) { cfg.debug = l }
+}
+
+// DialOptions adds grpc.DialOption values used for every connection
+// the pool opens, e.g. to configure TLS transport credentials in place
+// of the default insecure transport.
+func DialOptions(opts ...grpc.DialOption) ClientOption {
+	return func(cfg *clientConfig) { cfg.dialOpts = append(cfg.dialOpts, opts...) }
+}
+
+// NewClient dials addr and returns a Client backed by a pooled gRPC
+// connection to a Server listening there.
+func NewClient(addr string, opts ...ClientOption) (*Client, error) {
+	cfg := &clientConfig{
+		minConns:            1,
+		maxConns:            4,
+		flushInterval:       200 * time.Millisecond,
+		batchSize:           64,
+		healthCheckInterval: 30 * time.Second,
+		debug:               func(v ...interface{}) {},
+		dialOpts: []grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(grpc.CallContentSubtype(gobCodecName)),
+		},
+	}
+	for _, o := range opts {
+		o(cfg)
+	}
+	pool, err := newConnPool(addr, cfg.minConns, cfg.maxConns, cfg.dialOpts)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		pool:          pool,
+		debug:         cfg.debug,
+		flushInterval: cfg.flushInterval,
+		batchSize:     cfg.batchSize,
+		stopFlush:     make(chan struct{}),
+		flushDone:     make(chan struct{}),
+	}
+	go c.backgroundLoop(cfg.healthCheckInterval)
+	return c, nil
+}
+
+// backgroundLoop periodically flushes buffered writes and health
+// checks idle pool connections until Close stops it.
+func (c *Client) backgroundLoop(healthCheckInterval time.Duration) {
+	defer close(c.flushDone)
+	flush := time.NewTicker(c.flushInterval)
+	defer flush.Stop()
+	health := time.NewTicker(healthCheckInterval)
+	defer health.Stop()
+	for {
+		select {
+		case <-c.stopFlush:
+			return
+		case <-flush.C:
+			if err := c.flushVisited(); err != nil {
+				c.debug("rpc: flush visited failed", err)
+			}
+			if err := c.flushRequests(); err != nil {
+				c.debug("rpc: flush requests failed", err)
+			}
+		case <-health.C:
+			c.pool.healthCheck(c.flushInterval)
+		}
+	}
+}
+
+// Close flushes any buffered writes and closes the connection pool.
+func (c *Client) Close() error {
+	close(c.stopFlush)
+	<-c.flushDone
+	if err := c.flushVisited(); err != nil {
+		return err
+	}
+	if err := c.flushRequests(); err != nil {
+		return err
+	}
+	return c.pool.close()
+}
+
+// Init implements the colly.Storage interface over gRPC.
+func (c *Client) Init() error {
+	conn, err := c.pool.get()
+	if err != nil {
+		return err
+	}
+	defer c.pool.put(conn)
+	_, err = NewStorageClient(conn).Init(context.Background(), &Empty{})
+	return err
+}
+
+// Visited implements the colly.Storage interface. Calls are buffered
+// and sent to the Server in batches to amortize round-trips.
+func (c *Client) Visited(id uint64) error {
+	c.mu.Lock()
+	if c.pendingVisitedSet == nil {
+		c.pendingVisitedSet = make(map[uint64]struct{})
+	}
+	c.pendingVisited = append(c.pendingVisited, id)
+	c.pendingVisitedSet[id] = struct{}{}
+	shouldFlush := len(c.pendingVisited) >= c.batchSize
+	c.mu.Unlock()
+	if shouldFlush {
+		return c.flushVisited()
+	}
+	return nil
+}
+
+// IsVisited implements the colly.Storage interface.
+func (c *Client) IsVisited(id uint64) (bool, error) {
+	c.mu.Lock()
+	_, pending := c.pendingVisitedSet[id]
+	c.mu.Unlock()
+	if pending {
+		return true, nil
+	}
+	conn, err := c.pool.get()
+	if err != nil {
+		return false, err
+	}
+	defer c.pool.put(conn)
+	reply, err := NewStorageClient(conn).IsVisited(context.Background(), &IsVisitedRequest{ID: id})
+	if err != nil {
+		return false, err
+	}
+	return reply.Visited, nil
+}
+
+// Cookies implements the colly.Storage interface.
+func (c *Client) Cookies(u *url.URL) string {
+	conn, err := c.pool.get()
+	if err != nil {
+		c.debug("rpc: Cookies failed", err)
+		return ""
+	}
+	defer c.pool.put(conn)
+	reply, err := NewStorageClient(conn).Cookies(context.Background(), &CookiesRequest{URL: u.String()})
+	if err != nil {
+		c.debug("rpc: Cookies failed", err)
+		return ""
+	}
+	return reply.Cookies
+}
+
+// SetCookies implements the colly.Storage interface.
+func (c *Client) SetCookies(u *url.URL, cookies string) {
+	conn, err := c.pool.get()
+	if err != nil {
+		c.debug("rpc: SetCookies failed", err)
+		return
+	}
+	defer c.pool.put(conn)
+	req := &SetCookiesRequest{URL: u.String(), Cookies: cookies}
+	if _, err := NewStorageClient(conn).SetCookies(context.Background(), req); err != nil {
+		c.debug("rpc: SetCookies failed", err)
+	}
+}
+
+// AddRequest implements the colly.Storage interface. Calls are
+// buffered and sent to the Server in batches to amortize round-trips.
+func (c *Client) AddRequest(request []byte) error {
+	c.mu.Lock()
+	c.pendingRequests = append(c.pendingRequests, request)
+	shouldFlush := len(c.pendingRequests) >= c.batchSize
+	c.mu.Unlock()
+	if shouldFlush {
+		return c.flushRequests()
+	}
+	return nil
+}
+
+// GetRequest implements the colly.Queue interface. Any buffered
+// AddRequest calls are flushed first so they are visible to dequeue.
+func (c *Client) GetRequest() ([]byte, error) {
+	if err := c.flushRequests(); err != nil {
+		return nil, err
+	}
+	conn, err := c.pool.get()
+	if err != nil {
+		return nil, err
+	}
+	defer c.pool.put(conn)
+	reply, err := NewStorageClient(conn).GetRequest(context.Background(), &Empty{})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, bolt.ErrEmptyQueue
+		}
+		return nil, err
+	}
+	return reply.Request, nil
+}
+
+// QueueSize implements the colly.Queue interface.
+func (c *Client) QueueSize() (int, error) {
+	if err := c.flushRequests(); err != nil {
+		return 0, err
+	}
+	conn, err := c.pool.get()
+	if err != nil {
+		return 0, err
+	}
+	defer c.pool.put(conn)
+	reply, err := NewStorageClient(conn).QueueSize(context.Background(), &Empty{})
+	if err != nil {
+		return 0, err
+	}
+	return reply.Size, nil
+}
+
+// flushVisited sends the buffered ids to the Server. ids stay in
+// pendingVisitedSet until the RPC actually completes, not merely until
+// they're dequeued here, so a concurrent IsVisited for one of them
+// keeps seeing it as visited instead of racing ahead of the batch and
+// finding nothing on the Server. On failure the ids are put back on
+// pendingVisited so the next flush retries them.
+func (c *Client) flushVisited() error {
+	c.mu.Lock()
+	ids := c.pendingVisited
+	c.pendingVisited = nil
+	c.mu.Unlock()
+	if len(ids) == 0 {
+		return nil
+	}
+	conn, err := c.pool.get()
+	if err != nil {
+		c.requeueVisited(ids)
+		return err
+	}
+	defer c.pool.put(conn)
+	_, err = NewStorageClient(conn).VisitedBatch(context.Background(), &VisitedBatchRequest{IDs: ids})
+	if err != nil {
+		c.requeueVisited(ids)
+		return err
+	}
+	c.mu.Lock()
+	for _, id := range ids {
+		delete(c.pendingVisitedSet, id)
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// requeueVisited puts ids back at the front of pendingVisited after a
+// failed flush, leaving them in pendingVisitedSet so IsVisited keeps
+// reporting them as visited until the retry succeeds.
+func (c *Client) requeueVisited(ids []uint64) {
+	c.mu.Lock()
+	c.pendingVisited = append(ids, c.pendingVisited...)
+	c.mu.Unlock()
+}
+
+func (c *Client) flushRequests() error {
+	c.mu.Lock()
+	requests := c.pendingRequests
+	c.pendingRequests = nil
+	c.mu.Unlock()
+	if len(requests) == 0 {
+		return nil
+	}
+	conn, err := c.pool.get()
+	if err != nil {
+		return err
+	}
+	defer c.pool.put(conn)
+	_, err = NewStorageClient(conn).AddRequestBatch(context.Background(), &AddRequestBatchRequest{Requests: requests})
+	return err
+}
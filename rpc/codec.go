@@ -0,0 +1,34 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// gobCodecName is the gRPC content-subtype used for this service's
+// messages in place of protobuf.
+const gobCodecName = "gob"
+
+func init() {
+	encoding.RegisterCodec(gobCodec{})
+}
+
+// gobCodec lets the Storage service exchange its plain Go structs over
+// gRPC by encoding them with encoding/gob instead of protobuf.
+type gobCodec struct{}
+
+func (gobCodec) Name() string { return gobCodecName }
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
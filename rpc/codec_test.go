@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGobCodecRoundTrip(t *testing.T) {
+	codec := gobCodec{}
+	tests := []interface{}{
+		&Empty{},
+		&VisitedRequest{ID: 42},
+		&VisitedBatchRequest{IDs: []uint64{1, 2, 3}},
+		&IsVisitedReply{Visited: true},
+		&CookiesRequest{URL: "https://example.com"},
+		&SetCookiesRequest{URL: "https://example.com", Cookies: "a=b"},
+		&AddRequestBatchRequest{Requests: [][]byte{[]byte("r1"), []byte("r2")}},
+		&GetRequestReply{Request: []byte("r")},
+		&QueueSizeReply{Size: 7},
+	}
+	for _, want := range tests {
+		data, err := codec.Marshal(want)
+		if err != nil {
+			t.Fatalf("Marshal(%#v): %v", want, err)
+		}
+		got := reflect.New(reflect.TypeOf(want).Elem()).Interface()
+		if err := codec.Unmarshal(data, got); err != nil {
+			t.Fatalf("Unmarshal(%#v): %v", want, err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("round trip mismatch: want %#v, got %#v", want, got)
+		}
+	}
+}
+
+func TestGobCodecName(t *testing.T) {
+	if name := (gobCodec{}).Name(); name != gobCodecName {
+		t.Errorf("Name() = %q, want %q", name, gobCodecName)
+	}
+}
@@ -0,0 +1,62 @@
+package rpc
+
+// Empty is used for RPCs that take or return no data.
+type Empty struct{}
+
+// VisitedRequest is the argument to the Visited RPC.
+type VisitedRequest struct {
+	ID uint64
+}
+
+// VisitedBatchRequest is the argument to the VisitedBatch RPC, used by
+// Client's write-behind buffer.
+type VisitedBatchRequest struct {
+	IDs []uint64
+}
+
+// IsVisitedRequest is the argument to the IsVisited RPC.
+type IsVisitedRequest struct {
+	ID uint64
+}
+
+// IsVisitedReply is the result of the IsVisited RPC.
+type IsVisitedReply struct {
+	Visited bool
+}
+
+// CookiesRequest is the argument to the Cookies RPC.
+type CookiesRequest struct {
+	URL string
+}
+
+// CookiesReply is the result of the Cookies RPC.
+type CookiesReply struct {
+	Cookies string
+}
+
+// SetCookiesRequest is the argument to the SetCookies RPC.
+type SetCookiesRequest struct {
+	URL     string
+	Cookies string
+}
+
+// AddRequestRequest is the argument to the AddRequest RPC.
+type AddRequestRequest struct {
+	Request []byte
+}
+
+// AddRequestBatchRequest is the argument to the AddRequestBatch RPC,
+// used by Client's write-behind buffer.
+type AddRequestBatchRequest struct {
+	Requests [][]byte
+}
+
+// GetRequestReply is the result of the GetRequest RPC.
+type GetRequestReply struct {
+	Request []byte
+}
+
+// QueueSizeReply is the result of the QueueSize RPC.
+type QueueSizeReply struct {
+	Size int
+}
@@ -0,0 +1,177 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// errPoolClosed is returned by get and surfaces from put/healthCheck as
+// a no-op once close has run.
+var errPoolClosed = errors.New("rpc: connection pool is closed")
+
+// connPool is a fixed-capacity pool of gRPC connections to a single
+// Server. It opens min connections eagerly and dials further ones, up
+// to max, on demand.
+type connPool struct {
+	target   string
+	dialOpts []grpc.DialOption
+
+	mu      sync.Mutex
+	numOpen int
+	max     int
+	closed  bool
+
+	idle chan *grpc.ClientConn
+}
+
+func newConnPool(target string, min, max int, dialOpts []grpc.DialOption) (*connPool, error) {
+	if max < 1 {
+		max = 1
+	}
+	if min > max {
+		min = max
+	}
+	p := &connPool{
+		target:   target,
+		dialOpts: dialOpts,
+		max:      max,
+		idle:     make(chan *grpc.ClientConn, max),
+	}
+	for i := 0; i < min; i++ {
+		conn, err := p.dial()
+		if err != nil {
+			p.close()
+			return nil, err
+		}
+		p.idle <- conn
+	}
+	return p, nil
+}
+
+func (p *connPool) dial() (*grpc.ClientConn, error) {
+	conn, err := grpc.Dial(p.target, p.dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.numOpen++
+	p.mu.Unlock()
+	return conn, nil
+}
+
+// get returns an idle connection, dialing a new one if the pool has
+// not reached its max size, or blocking for one to be returned
+// otherwise. It returns errPoolClosed once close has run.
+func (p *connPool) get() (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, errPoolClosed
+	}
+	select {
+	case conn, ok := <-p.idle:
+		p.mu.Unlock()
+		if !ok {
+			return nil, errPoolClosed
+		}
+		return conn, nil
+	default:
+	}
+	if p.numOpen >= p.max {
+		p.mu.Unlock()
+		conn, ok := <-p.idle
+		if !ok {
+			return nil, errPoolClosed
+		}
+		return conn, nil
+	}
+	p.numOpen++
+	p.mu.Unlock()
+	conn, err := grpc.Dial(p.target, p.dialOpts...)
+	if err != nil {
+		p.mu.Lock()
+		p.numOpen--
+		p.mu.Unlock()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// put returns conn to the pool, or closes it if the pool is full or
+// already closed.
+func (p *connPool) put(conn *grpc.ClientConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		conn.Close()
+		return
+	}
+	select {
+	case p.idle <- conn:
+	default:
+		conn.Close()
+		p.numOpen--
+	}
+}
+
+// healthCheck pings every currently idle connection and replaces any
+// that fail to respond.
+func (p *connPool) healthCheck(timeout time.Duration) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	n := len(p.idle)
+	p.mu.Unlock()
+	for i := 0; i < n; i++ {
+		var conn *grpc.ClientConn
+		select {
+		case conn = <-p.idle:
+		default:
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		_, err := NewStorageClient(conn).Ping(ctx, &Empty{})
+		cancel()
+		if err != nil {
+			conn.Close()
+			p.mu.Lock()
+			p.numOpen--
+			closed := p.closed
+			p.mu.Unlock()
+			if closed {
+				return
+			}
+			if replacement, err := p.dial(); err == nil {
+				p.put(replacement)
+			}
+			continue
+		}
+		p.put(conn)
+	}
+}
+
+// close marks the pool closed so concurrent get/put calls stop
+// touching idle, then closes every connection left in it.
+func (p *connPool) close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.idle)
+	p.mu.Unlock()
+	var firstErr error
+	for conn := range p.idle {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,20 @@
+// Package rpc lets several Colly workers share one bolt.Storage over
+// the network, so a single bbolt file can back a distributed crawl
+// without switching the queue/visited/cookie store to Redis.
+//
+// Server wraps a local *bolt.Storage and exposes it over gRPC. Client
+// dials a Server and implements the same colly.Storage and colly.Queue
+// interfaces as bolt.Storage, so it can be used as a drop-in
+// replacement in workers that don't hold the bbolt file themselves.
+//
+// The service's messages are plain Go structs encoded with gob rather
+// than protobuf, via the gob codec registered in codec.go; there is no
+// .proto file to regenerate. gRPC otherwise defaults every call to its
+// built-in proto codec regardless of what's registered, so Client dials
+// with gobCodecName set as the call content-subtype and Server forces
+// the same codec, making gob the codec actually used on the wire.
+package rpc
+
+// serviceName is the gRPC service name the Server registers under and
+// the Client's stubs call into.
+const serviceName = "bolt.rpc.Storage"
@@ -0,0 +1,128 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/url"
+
+	bolt "github.com/felix/colly-bolt-storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements StorageServer over a local *bolt.Storage.
+type Server struct {
+	storage *bolt.Storage
+}
+
+// NewServer wraps storage for use with Serve or a custom gRPC server.
+func NewServer(storage *bolt.Storage) *Server {
+	return &Server{storage: storage}
+}
+
+// Serve starts a gRPC server wrapping server and listens on addr until
+// the returned *grpc.Server is stopped.
+func Serve(addr string, server *Server) (*grpc.Server, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	grpcServer := grpc.NewServer(grpc.ForceServerCodec(gobCodec{}))
+	RegisterStorageServer(grpcServer, server)
+	go grpcServer.Serve(l)
+	return grpcServer, nil
+}
+
+// Ping is used by Client's connection health checks.
+func (s *Server) Ping(_ context.Context, _ *Empty) (*Empty, error) {
+	return &Empty{}, nil
+}
+
+// Init implements the colly.Storage interface over gRPC.
+func (s *Server) Init(_ context.Context, _ *Empty) (*Empty, error) {
+	return &Empty{}, s.storage.Init()
+}
+
+// Visited implements the colly.Storage interface over gRPC.
+func (s *Server) Visited(_ context.Context, req *VisitedRequest) (*Empty, error) {
+	return &Empty{}, s.storage.Visited(req.ID)
+}
+
+// VisitedBatch applies a batch of Visited ids, for Client's
+// write-behind buffer.
+func (s *Server) VisitedBatch(_ context.Context, req *VisitedBatchRequest) (*Empty, error) {
+	for _, id := range req.IDs {
+		if err := s.storage.Visited(id); err != nil {
+			return nil, err
+		}
+	}
+	return &Empty{}, nil
+}
+
+// IsVisited implements the colly.Storage interface over gRPC.
+func (s *Server) IsVisited(_ context.Context, req *IsVisitedRequest) (*IsVisitedReply, error) {
+	visited, err := s.storage.IsVisited(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &IsVisitedReply{Visited: visited}, nil
+}
+
+// Cookies implements the colly.Storage interface over gRPC.
+func (s *Server) Cookies(_ context.Context, req *CookiesRequest) (*CookiesReply, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	return &CookiesReply{Cookies: s.storage.Cookies(u)}, nil
+}
+
+// SetCookies implements the colly.Storage interface over gRPC.
+func (s *Server) SetCookies(_ context.Context, req *SetCookiesRequest) (*Empty, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	s.storage.SetCookies(u, req.Cookies)
+	return &Empty{}, nil
+}
+
+// AddRequest implements the colly.Storage interface over gRPC.
+func (s *Server) AddRequest(_ context.Context, req *AddRequestRequest) (*Empty, error) {
+	return &Empty{}, s.storage.AddRequest(req.Request)
+}
+
+// AddRequestBatch applies a batch of requests, for Client's
+// write-behind buffer.
+func (s *Server) AddRequestBatch(_ context.Context, req *AddRequestBatchRequest) (*Empty, error) {
+	for _, request := range req.Requests {
+		if err := s.storage.AddRequest(request); err != nil {
+			return nil, err
+		}
+	}
+	return &Empty{}, nil
+}
+
+// GetRequest implements the colly.Queue interface over gRPC. An empty
+// queue is reported as a codes.NotFound status, which Client
+// translates back to bolt.ErrEmptyQueue.
+func (s *Server) GetRequest(_ context.Context, _ *Empty) (*GetRequestReply, error) {
+	request, err := s.storage.GetRequest()
+	if err == bolt.ErrEmptyQueue {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &GetRequestReply{Request: request}, nil
+}
+
+// QueueSize implements the colly.Queue interface over gRPC.
+func (s *Server) QueueSize(_ context.Context, _ *Empty) (*QueueSizeReply, error) {
+	size, err := s.storage.QueueSize()
+	if err != nil {
+		return nil, err
+	}
+	return &QueueSizeReply{Size: size}, nil
+}
@@ -0,0 +1,325 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// StorageServer is the server API for the Storage gRPC service.
+type StorageServer interface {
+	Init(context.Context, *Empty) (*Empty, error)
+	Visited(context.Context, *VisitedRequest) (*Empty, error)
+	VisitedBatch(context.Context, *VisitedBatchRequest) (*Empty, error)
+	IsVisited(context.Context, *IsVisitedRequest) (*IsVisitedReply, error)
+	Cookies(context.Context, *CookiesRequest) (*CookiesReply, error)
+	SetCookies(context.Context, *SetCookiesRequest) (*Empty, error)
+	AddRequest(context.Context, *AddRequestRequest) (*Empty, error)
+	AddRequestBatch(context.Context, *AddRequestBatchRequest) (*Empty, error)
+	GetRequest(context.Context, *Empty) (*GetRequestReply, error)
+	QueueSize(context.Context, *Empty) (*QueueSizeReply, error)
+	Ping(context.Context, *Empty) (*Empty, error)
+}
+
+// RegisterStorageServer registers srv with s under the Storage gRPC
+// service.
+func RegisterStorageServer(s *grpc.Server, srv StorageServer) {
+	s.RegisterService(&storageServiceDesc, srv)
+}
+
+// StorageClient is the client API for the Storage gRPC service.
+type StorageClient interface {
+	Init(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	Visited(ctx context.Context, in *VisitedRequest, opts ...grpc.CallOption) (*Empty, error)
+	VisitedBatch(ctx context.Context, in *VisitedBatchRequest, opts ...grpc.CallOption) (*Empty, error)
+	IsVisited(ctx context.Context, in *IsVisitedRequest, opts ...grpc.CallOption) (*IsVisitedReply, error)
+	Cookies(ctx context.Context, in *CookiesRequest, opts ...grpc.CallOption) (*CookiesReply, error)
+	SetCookies(ctx context.Context, in *SetCookiesRequest, opts ...grpc.CallOption) (*Empty, error)
+	AddRequest(ctx context.Context, in *AddRequestRequest, opts ...grpc.CallOption) (*Empty, error)
+	AddRequestBatch(ctx context.Context, in *AddRequestBatchRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetRequest(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetRequestReply, error)
+	QueueSize(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*QueueSizeReply, error)
+	Ping(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type storageClient struct {
+	cc *grpc.ClientConn
+}
+
+// NewStorageClient returns a StorageClient that calls methods on cc.
+func NewStorageClient(cc *grpc.ClientConn) StorageClient {
+	return &storageClient{cc: cc}
+}
+
+func (c *storageClient) Init(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/Init", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Visited(ctx context.Context, in *VisitedRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/Visited", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) VisitedBatch(ctx context.Context, in *VisitedBatchRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/VisitedBatch", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) IsVisited(ctx context.Context, in *IsVisitedRequest, opts ...grpc.CallOption) (*IsVisitedReply, error) {
+	out := new(IsVisitedReply)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/IsVisited", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Cookies(ctx context.Context, in *CookiesRequest, opts ...grpc.CallOption) (*CookiesReply, error) {
+	out := new(CookiesReply)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/Cookies", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetCookies(ctx context.Context, in *SetCookiesRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/SetCookies", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) AddRequest(ctx context.Context, in *AddRequestRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/AddRequest", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) AddRequestBatch(ctx context.Context, in *AddRequestBatchRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/AddRequestBatch", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetRequest(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetRequestReply, error) {
+	out := new(GetRequestReply)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/GetRequest", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) QueueSize(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*QueueSizeReply, error) {
+	out := new(QueueSizeReply)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/QueueSize", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Ping(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/Ping", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Storage_Init_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Init(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Init"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Init(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Visited_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VisitedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Visited(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Visited"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Visited(ctx, req.(*VisitedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_VisitedBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VisitedBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).VisitedBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/VisitedBatch"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).VisitedBatch(ctx, req.(*VisitedBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_IsVisited_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsVisitedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).IsVisited(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/IsVisited"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).IsVisited(ctx, req.(*IsVisitedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Cookies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CookiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Cookies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Cookies"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Cookies(ctx, req.(*CookiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SetCookies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCookiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetCookies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/SetCookies"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetCookies(ctx, req.(*SetCookiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_AddRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).AddRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/AddRequest"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).AddRequest(ctx, req.(*AddRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_AddRequestBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRequestBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).AddRequestBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/AddRequestBatch"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).AddRequestBatch(ctx, req.(*AddRequestBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/GetRequest"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetRequest(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_QueueSize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).QueueSize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/QueueSize"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).QueueSize(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Ping"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Ping(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var storageServiceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*StorageServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Init", Handler: _Storage_Init_Handler},
+		{MethodName: "Visited", Handler: _Storage_Visited_Handler},
+		{MethodName: "VisitedBatch", Handler: _Storage_VisitedBatch_Handler},
+		{MethodName: "IsVisited", Handler: _Storage_IsVisited_Handler},
+		{MethodName: "Cookies", Handler: _Storage_Cookies_Handler},
+		{MethodName: "SetCookies", Handler: _Storage_SetCookies_Handler},
+		{MethodName: "AddRequest", Handler: _Storage_AddRequest_Handler},
+		{MethodName: "AddRequestBatch", Handler: _Storage_AddRequestBatch_Handler},
+		{MethodName: "GetRequest", Handler: _Storage_GetRequest_Handler},
+		{MethodName: "QueueSize", Handler: _Storage_QueueSize_Handler},
+		{MethodName: "Ping", Handler: _Storage_Ping_Handler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "",
+}
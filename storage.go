@@ -1,18 +1,30 @@
 package bolt
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"net/url"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/bits-and-blooms/bloom/v3"
 	"go.etcd.io/bbolt"
 )
 
 var (
-	bucketRequests = []byte("requests")
-	bucketCookies  = []byte("cookies")
-	bucketQueue    = []byte("queue")
+	bucketRequests  = []byte("requests")
+	bucketCookies   = []byte("cookies")
+	bucketQueue     = []byte("queue")
+	bucketQueueMeta = []byte("queue_meta")
+
+	// queueCursorKey holds the host GetRequest should resume the
+	// round-robin rotation from on the next call.
+	queueCursorKey = []byte("cursor")
 
 	// ErrEmptyQueue is returned when an URL is requested from an empty queue.
 	ErrEmptyQueue = fmt.Errorf("queue is empty")
@@ -20,12 +32,30 @@ var (
 
 // Storage is a implementation for colly/queue and colly/storage
 type Storage struct {
-	db      *bbolt.DB
-	mode    os.FileMode
-	options *bbolt.Options
-	debug   Logger
+	// db is behind an atomic pointer because sweepExpiredLoop reads it
+	// on every tick from its own goroutine while Restore closes,
+	// replaces and reopens it.
+	db         atomic.Pointer[bbolt.DB]
+	path       string
+	mode       os.FileMode
+	options    *bbolt.Options
+	debug      Logger
+	visitedTTL time.Duration
+	cookieTTL  time.Duration
+	stopSweep  chan struct{}
+	sweepDone  chan struct{}
+	codec      Codec
+	bloom      *bloom.BloomFilter
+	bloomItems uint64
+	bloomFP    float64
+	bloomMu    sync.Mutex
+	hostDelay  func(host string) time.Duration
 }
 
+// defaultSweepInterval is how often the background goroutine scans for
+// expired Visited/cookie entries when a TTL option is in use.
+const defaultSweepInterval = time.Minute
+
 // Logger is the interface used for debug logging.
 type Logger func(...interface{})
 
@@ -56,10 +86,55 @@ func Debug(l Logger) Option {
 	}
 }
 
+// VisitedTTL makes Visited entries expire after d, so the corresponding
+// URLs become eligible for IsVisited to report false again (e.g. for
+// periodic recrawl). By default entries never expire.
+func VisitedTTL(d time.Duration) Option {
+	return func(s *Storage) error {
+		s.visitedTTL = d
+		return nil
+	}
+}
+
+// CookieTTL makes per-URL cookie entries expire after d. By default
+// cookies never expire.
+func CookieTTL(d time.Duration) Option {
+	return func(s *Storage) error {
+		s.cookieTTL = d
+		return nil
+	}
+}
+
+// BloomFilter fronts IsVisited with an in-memory Bloom filter sized for
+// expectedItems entries at fpRate false-positive rate, so that negative
+// lookups skip the bbolt read transaction entirely. The filter is
+// (re)built from bucketRequests by New and Init, and bbolt remains the
+// source of truth for positive lookups.
+func BloomFilter(expectedItems uint64, fpRate float64) Option {
+	return func(s *Storage) error {
+		s.bloomItems = expectedItems
+		s.bloomFP = fpRate
+		s.bloom = bloom.NewWithEstimates(expectedItems, fpRate)
+		return nil
+	}
+}
+
+// HostDelay lets GetRequest skip hosts that are still rate-limited. f
+// is called with a candidate host during dequeue; a positive duration
+// means the host is not yet ready and GetRequest tries the next host
+// in rotation instead.
+func HostDelay(f func(host string) time.Duration) Option {
+	return func(s *Storage) error {
+		s.hostDelay = f
+		return nil
+	}
+}
+
 // New creates a new storage implementation for Colly.
 // A database will be created at the provided path if it does not already exist.
 func New(path string, opts ...Option) (*Storage, error) {
 	out := &Storage{
+		path:    path,
 		options: bbolt.DefaultOptions,
 		mode:    0666,
 		debug:   func(v ...interface{}) {},
@@ -69,28 +144,216 @@ func New(path string, opts ...Option) (*Storage, error) {
 			return nil, err
 		}
 	}
-	var err error
 	out.debug("bolt: using file", path, "mode", out.mode)
-	out.db, err = bbolt.Open(path, out.mode, out.options)
+	db, err := bbolt.Open(path, out.mode, out.options)
 	if err != nil {
 		return nil, err
 	}
+	out.db.Store(db)
+	if out.visitedTTL > 0 || out.cookieTTL > 0 {
+		out.startSweep()
+	}
+	if err := out.rebuildBloomFilter(); err != nil {
+		return nil, err
+	}
 	return out, nil
 }
 
+// rebuildBloomFilter replaces the Bloom filter, if one is configured,
+// with a fresh one populated from the current contents of
+// bucketRequests. It is a no-op if no BloomFilter option was given. A
+// fresh filter, rather than adding into the existing one, matters for
+// Restore: without it, keys visited before a restore would stay
+// "maybe present" forever even after a smaller/older snapshot replaces
+// the database, and the filter could only grow toward saturation
+// across repeated restores.
+func (s *Storage) rebuildBloomFilter() error {
+	if s.bloom == nil {
+		return nil
+	}
+	fresh := bloom.NewWithEstimates(s.bloomItems, s.bloomFP)
+	err := s.db.Load().View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(bucketRequests)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			fresh.Add(k)
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	s.bloomMu.Lock()
+	s.bloom = fresh
+	s.bloomMu.Unlock()
+	return nil
+}
+
 // Close ensures the database is left in a valid state.
 func (s *Storage) Close() error {
-	return s.db.Close()
+	if s.stopSweep != nil {
+		close(s.stopSweep)
+		<-s.sweepDone
+	}
+	return s.db.Load().Close()
+}
+
+// startSweep starts the background sweep goroutine and records its
+// stop/done channels on s so Close and Restore can pause it. stop and
+// done are captured as locals by sweepExpiredLoop rather than read off
+// s on every tick, so a later startSweep call (from Restore restarting
+// the loop) reassigning s.stopSweep/s.sweepDone cannot race with the
+// previous loop's use of its own channels.
+func (s *Storage) startSweep() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	s.stopSweep = stop
+	s.sweepDone = done
+	go s.sweepExpiredLoop(stop, done)
+}
+
+// sweepExpiredLoop periodically removes expired Visited and cookie
+// entries until stop is closed.
+func (s *Storage) sweepExpiredLoop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(defaultSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.sweepExpired()
+		}
+	}
+}
+
+// sweepExpired deletes expired entries from bucketRequests and
+// bucketCookies using a cursor.
+func (s *Storage) sweepExpired() {
+	now := time.Now()
+	s.db.Load().Update(func(tx *bbolt.Tx) error {
+		if s.visitedTTL > 0 {
+			sweepExpiredEntries(tx.Bucket(bucketRequests), now)
+		}
+		if s.cookieTTL > 0 {
+			sweepExpiredEntries(tx.Bucket(bucketCookies), now)
+		}
+		return nil
+	})
+}
+
+// sweepExpiredEntries deletes every key in b whose value carries an
+// expiry (the first 8 bytes) that is in the past. It is a no-op if b
+// has not been created yet.
+func sweepExpiredEntries(b *bbolt.Bucket, now time.Time) {
+	if b == nil {
+		return
+	}
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if len(v) < expiryLen {
+			continue
+		}
+		if exp := decodeExpiry(v); !exp.IsZero() && now.After(exp) {
+			c.Delete()
+		}
+	}
+}
+
+// Backup writes a consistent snapshot of the crawl state (visited set,
+// cookies, queue) to a new database file at path, created with the
+// given mode. It is safe to call while the crawler is running.
+func (s *Storage) Backup(path string, mode os.FileMode) error {
+	return s.db.Load().View(func(tx *bbolt.Tx) error {
+		return tx.CopyFile(path, mode)
+	})
+}
+
+// WriteTo writes a consistent snapshot of the crawl state to w, in the
+// same format used by Backup. It returns the number of bytes written.
+func (s *Storage) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	err := s.db.Load().View(func(tx *bbolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+	return n, err
+}
+
+// Restore replaces the storage's database file with the snapshot at
+// path, such as one produced by Backup. The current database is closed
+// first and reopened afterwards; callers must not use the Storage
+// concurrently with Restore. If a TTL option started a background
+// sweep goroutine, it is paused for the duration of the swap and
+// restarted against the reopened database afterwards. If a BloomFilter
+// option is configured, the filter is rebuilt from the restored
+// database so it doesn't keep reporting false negatives for keys this
+// process never Add'ed itself.
+func (s *Storage) Restore(path string) error {
+	sweeping := s.stopSweep != nil
+	if sweeping {
+		close(s.stopSweep)
+		<-s.sweepDone
+	}
+	if err := s.db.Load().Close(); err != nil {
+		return err
+	}
+	// replaceFile, not os.Rename: path is commonly a snapshot pulled
+	// down from elsewhere (that's the point of Backup/WriteTo), so it
+	// routinely lives on a different filesystem than s.path and a
+	// rename would fail with EXDEV. Reopen s.path regardless of
+	// whether the replace succeeded, so a failure here never leaves
+	// the Storage stuck pointing at a closed db.
+	replaceErr := replaceFile(path, s.path, s.mode)
+	db, err := bbolt.Open(s.path, s.mode, s.options)
+	if err != nil {
+		return err
+	}
+	s.db.Store(db)
+	if sweeping {
+		s.startSweep()
+	}
+	if replaceErr != nil {
+		return replaceErr
+	}
+	return s.rebuildBloomFilter()
+}
+
+// replaceFile copies the contents of src into dst, created with mode
+// if it doesn't already exist, and removes src once the copy succeeds.
+func replaceFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
 }
 
 // Init implements the colly.Storage interface.
 func (s *Storage) Init() error {
-	return s.db.Update(func(tx *bbolt.Tx) error {
+	err := s.db.Load().Update(func(tx *bbolt.Tx) error {
 		s.debug("bolt: creating buckets")
 		for _, b := range [][]byte{
 			bucketRequests,
 			bucketCookies,
 			bucketQueue,
+			bucketQueueMeta,
 		} {
 			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
 				return err
@@ -98,78 +361,414 @@ func (s *Storage) Init() error {
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	return s.rebuildBloomFilter()
 }
 
 // Visited implements the colly.Storage interface.
 func (s *Storage) Visited(id uint64) error {
-	return s.db.Update(func(tx *bbolt.Tx) error {
-		return tx.Bucket(bucketRequests).Put(u64ToBytes(id), []byte{})
+	key := u64ToBytes(id)
+	var expiresAt time.Time
+	if s.visitedTTL > 0 {
+		expiresAt = time.Now().Add(s.visitedTTL)
+	}
+	err := s.db.Load().Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketRequests).Put(key, encodeExpiry(expiresAt))
 	})
+	if err != nil {
+		return err
+	}
+	if s.bloom != nil {
+		s.bloomMu.Lock()
+		s.bloom.Add(key)
+		s.bloomMu.Unlock()
+	}
+	return nil
 }
 
-// IsVisited implements the colly.Storage interface.
+// IsVisited implements the colly.Storage interface. A Visited entry
+// whose VisitedTTL has elapsed is treated as not visited and is lazily
+// deleted. If a BloomFilter option is configured, a negative match
+// skips the bbolt read transaction entirely.
 func (s *Storage) IsVisited(id uint64) (bool, error) {
-	var isVisited bool
-	err := s.db.View(func(tx *bbolt.Tx) error {
-		isVisited = tx.Bucket(bucketRequests).Get(u64ToBytes(id)) != nil
+	key := u64ToBytes(id)
+	if s.bloom != nil {
+		s.bloomMu.Lock()
+		maybeVisited := s.bloom.Test(key)
+		s.bloomMu.Unlock()
+		if !maybeVisited {
+			return false, nil
+		}
+	}
+	var visited, expired bool
+	err := s.db.Load().View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket(bucketRequests).Get(key)
+		if v == nil {
+			return nil
+		}
+		// A value shorter than expiryLen predates VisitedTTL, which
+		// used to Put an empty marker value. Treat it as visited with
+		// no expiry instead of letting decodeExpiry panic on it.
+		if len(v) < expiryLen {
+			visited = true
+			return nil
+		}
+		if exp := decodeExpiry(v); !exp.IsZero() && time.Now().After(exp) {
+			expired = true
+			return nil
+		}
+		visited = true
 		return nil
 	})
-	return isVisited, err
+	if err != nil {
+		return false, err
+	}
+	if expired {
+		err = s.db.Load().Update(func(tx *bbolt.Tx) error {
+			return tx.Bucket(bucketRequests).Delete(key)
+		})
+	}
+	return visited, err
 }
 
-// Cookies implements the colly.Storage interface.
+// Cookies implements the colly.Storage interface. A cookie entry whose
+// CookieTTL has elapsed is treated as absent and is lazily deleted.
 func (s *Storage) Cookies(u *url.URL) string {
+	key := []byte(u.String())
 	var cookies string
-	s.db.View(func(tx *bbolt.Tx) error {
-		cookies = string(tx.Bucket(bucketCookies).Get([]byte(u.String())))
+	var expired bool
+	s.db.Load().View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket(bucketCookies).Get(key)
+		if len(v) < expiryLen {
+			return nil
+		}
+		if exp := decodeExpiry(v[:expiryLen]); !exp.IsZero() && time.Now().After(exp) {
+			expired = true
+			return nil
+		}
+		payload, err := s.decode(v[expiryLen:])
+		if err != nil {
+			return err
+		}
+		cookies = string(payload)
 		return nil
 	})
+	if expired {
+		s.db.Load().Update(func(tx *bbolt.Tx) error {
+			return tx.Bucket(bucketCookies).Delete(key)
+		})
+	}
 	return cookies
 }
 
 // SetCookies implements the colly.Storage interface.
 func (s *Storage) SetCookies(u *url.URL, cookies string) {
-	s.db.Update(func(tx *bbolt.Tx) error {
-		return tx.Bucket(bucketCookies).Put([]byte(u.String()), []byte(cookies))
+	var expiresAt time.Time
+	if s.cookieTTL > 0 {
+		expiresAt = time.Now().Add(s.cookieTTL)
+	}
+	s.db.Load().Update(func(tx *bbolt.Tx) error {
+		payload, err := s.encode([]byte(cookies))
+		if err != nil {
+			return err
+		}
+		value := append(encodeExpiry(expiresAt), payload...)
+		return tx.Bucket(bucketCookies).Put([]byte(u.String()), value)
 	})
 }
 
+// expiryLen is the size, in bytes, of the expiry timestamp stored
+// alongside bucketRequests and bucketCookies values.
+const expiryLen = 8
+
+// encodeExpiry encodes t as an 8-byte big-endian unix-nano timestamp.
+// The zero Time encodes as all-zero bytes, meaning "never expires".
+func encodeExpiry(t time.Time) []byte {
+	b := make([]byte, expiryLen)
+	var n int64
+	if !t.IsZero() {
+		n = t.UnixNano()
+	}
+	binary.BigEndian.PutUint64(b, uint64(n))
+	return b
+}
+
+// decodeExpiry is the inverse of encodeExpiry.
+func decodeExpiry(b []byte) time.Time {
+	n := int64(binary.BigEndian.Uint64(b[:expiryLen]))
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// encode runs v through the configured Codec, if any.
+func (s *Storage) encode(v []byte) ([]byte, error) {
+	if s.codec == nil {
+		return v, nil
+	}
+	return s.codec.Encode(v)
+}
+
+// decode runs v through the configured Codec, if any.
+func (s *Storage) decode(v []byte) ([]byte, error) {
+	if s.codec == nil {
+		return v, nil
+	}
+	return s.codec.Decode(v)
+}
+
+// requestOptions holds the settings applied by RequestOption funcs.
+type requestOptions struct {
+	priority  int
+	notBefore time.Time
+}
+
+// RequestOption configures how a request is enqueued by AddRequestWithOptions.
+type RequestOption func(*requestOptions)
+
+// Priority sets the priority of a queued request. Within a host's
+// shard, higher values are dequeued before lower ones; requests with
+// equal priority and NotBefore are dequeued in the order they were
+// added. Across hosts, GetRequest rotates round-robin, so priority does
+// not order requests for different hosts. The default priority is 0. p
+// is clamped to the range of int32.
+func Priority(p int) RequestOption {
+	return func(o *requestOptions) {
+		o.priority = p
+	}
+}
+
+// NotBefore marks a request as not eligible for GetRequest until time t
+// has passed, allowing callers to implement politeness delays or
+// scheduled (re)crawls directly in the persistent queue.
+func NotBefore(t time.Time) RequestOption {
+	return func(o *requestOptions) {
+		o.notBefore = t
+	}
+}
+
 // AddRequest implements the colly.Storage interface.
 func (s *Storage) AddRequest(request []byte) error {
-	return s.db.Update(func(tx *bbolt.Tx) error {
-		bucket := tx.Bucket(bucketQueue)
-		n, err := bucket.NextSequence()
+	return s.AddRequestWithOptions(request)
+}
+
+// AddRequestWithOptions enqueues request with the given RequestOptions
+// applied, e.g. Priority and NotBefore. Requests are sharded into a
+// sub-bucket of bucketQueue keyed by the host of the request's URL, so
+// that GetRequest can dequeue fairly across hosts.
+func (s *Storage) AddRequestWithOptions(request []byte, opts ...RequestOption) error {
+	ro := &requestOptions{}
+	for _, o := range opts {
+		o(ro)
+	}
+	host, err := requestHost(request)
+	if err != nil || host == "" {
+		s.debug("bolt: could not determine request host, using default shard", err)
+		host = defaultQueueHost
+	}
+	return s.db.Load().Update(func(tx *bbolt.Tx) error {
+		hostBucket, err := tx.Bucket(bucketQueue).CreateBucketIfNotExists([]byte(host))
+		if err != nil {
+			return err
+		}
+		seq, err := hostBucket.NextSequence()
 		if err != nil {
 			return err
 		}
-		return bucket.Put(u64ToBytes(n), request)
+		value, err := s.encode(request)
+		if err != nil {
+			return err
+		}
+		return hostBucket.Put(queueKey(ro.priority, ro.notBefore, seq), value)
 	})
 }
 
-// GetRequest implements the colly.Storage interface.
+// defaultQueueHost shards requests whose host cannot be determined.
+const defaultQueueHost = "unknown"
+
+// requestHost extracts the URL host from a serialized colly request.
+func requestHost(request []byte) (string, error) {
+	var r struct {
+		URL string
+	}
+	if err := json.Unmarshal(request, &r); err != nil {
+		return "", err
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return "", err
+	}
+	return u.Host, nil
+}
+
+// GetRequest implements the colly.Queue interface. Hosts are visited in
+// round-robin order, skipping any host for which HostDelay reports a
+// positive delay. Within a host, entries are returned in priority
+// order (highest first), and entries whose NotBefore time has not yet
+// arrived are skipped. A host's sub-bucket is removed once it is
+// drained, so a long crawl touching many hosts doesn't accumulate
+// permanent empty shards. ErrEmptyQueue is returned if no entry is
+// ready.
 func (s *Storage) GetRequest() ([]byte, error) {
 	var request []byte
-	err := s.db.Update(func(tx *bbolt.Tx) error {
-		c := tx.Bucket(bucketQueue).Cursor()
-		_, request = c.First()
-		if request == nil {
+	now := time.Now()
+	err := s.db.Load().Update(func(tx *bbolt.Tx) error {
+		qb := tx.Bucket(bucketQueue)
+		meta := tx.Bucket(bucketQueueMeta)
+		hosts, err := queueHosts(qb)
+		if err != nil {
+			return err
+		}
+		if len(hosts) == 0 {
 			return ErrEmptyQueue
 		}
-		return c.Delete()
+		start := nextHostIndex(hosts, meta.Get(queueCursorKey))
+		for i := 0; i < len(hosts); i++ {
+			idx := (start + i) % len(hosts)
+			host := hosts[idx]
+			if s.hostDelay != nil && s.hostDelay(host) > 0 {
+				continue
+			}
+			hostBucket := qb.Bucket([]byte(host))
+			c := hostBucket.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				if queueKeyNotBefore(k).After(now) {
+					continue
+				}
+				decoded, err := s.decode(v)
+				if err != nil {
+					return err
+				}
+				if err := c.Delete(); err != nil {
+					return err
+				}
+				if k, _ := hostBucket.Cursor().First(); k == nil {
+					if err := qb.DeleteBucket([]byte(host)); err != nil {
+						return err
+					}
+				}
+				request = decoded
+				// Record the host rotation should resume from next
+				// time, not the one just dequeued: if that host's
+				// bucket was just deleted above, nextHostIndex
+				// wouldn't find it in the next call's host list and
+				// would restart from index 0 instead of continuing
+				// the rotation.
+				next := hosts[(idx+1)%len(hosts)]
+				return meta.Put(queueCursorKey, []byte(next))
+			}
+		}
+		return ErrEmptyQueue
 	})
 	return request, err
 }
 
-// QueueSize implements the colly.Queue interface.
+// queueHosts lists the host sub-buckets of bucketQueue, in bbolt's
+// (sorted) key order.
+func queueHosts(qb *bbolt.Bucket) ([]string, error) {
+	var hosts []string
+	err := qb.ForEach(func(k, v []byte) error {
+		if v == nil {
+			hosts = append(hosts, string(k))
+		}
+		return nil
+	})
+	return hosts, err
+}
+
+// nextHostIndex returns the index in hosts to resume round-robin
+// dequeue from, given the host GetRequest recorded as the next one to
+// try. That host may no longer be in hosts (e.g. it was a
+// single-entry host drained since), in which case rotation restarts
+// from the beginning.
+func nextHostIndex(hosts []string, cursor []byte) int {
+	if cursor == nil {
+		return 0
+	}
+	for i, h := range hosts {
+		if h == string(cursor) {
+			return i
+		}
+	}
+	return 0
+}
+
+// queueKey encodes priority, notBefore and seq into a key whose byte
+// order matches dequeue order: highest priority first, then earliest
+// notBefore, then insertion order.
+func queueKey(priority int, notBefore time.Time, seq uint64) []byte {
+	key := make([]byte, 20)
+	binary.BigEndian.PutUint32(key[0:4], invertedPriority(priority))
+	var nb int64
+	if !notBefore.IsZero() {
+		nb = notBefore.UnixNano()
+	}
+	binary.BigEndian.PutUint64(key[4:12], uint64(nb))
+	binary.BigEndian.PutUint64(key[12:20], seq)
+	return key
+}
+
+// invertedPriority clamps priority to the range of int32 and maps it to
+// a uint32 that sorts in the opposite order, so that the highest
+// priority produces the lowest key bytes and is dequeued first.
+func invertedPriority(priority int) uint32 {
+	clamped := priority
+	if clamped > math.MaxInt32 {
+		clamped = math.MaxInt32
+	}
+	if clamped < math.MinInt32 {
+		clamped = math.MinInt32
+	}
+	ordered := uint32(int32(clamped)) ^ 0x80000000
+	return math.MaxUint32 - ordered
+}
+
+// queueKeyNotBefore extracts the notBefore time encoded in a queue key.
+func queueKeyNotBefore(key []byte) time.Time {
+	nb := int64(binary.BigEndian.Uint64(key[4:12]))
+	if nb == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nb)
+}
+
+// QueueSize implements the colly.Queue interface. It is the sum of the
+// queue sizes across all host shards.
 func (s *Storage) QueueSize() (int, error) {
 	var queueSize int
-	err := s.db.View(func(tx *bbolt.Tx) error {
-		queueSize = tx.Bucket(bucketQueue).Stats().KeyN
-		return nil
+	err := s.db.Load().View(func(tx *bbolt.Tx) error {
+		qb := tx.Bucket(bucketQueue)
+		return qb.ForEach(func(k, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			queueSize += qb.Bucket(k).Stats().KeyN
+			return nil
+		})
 	})
 	return queueSize, err
 }
 
+// QueueSizeByHost reports the number of queued requests per host, for
+// observability into per-host backlog.
+func (s *Storage) QueueSizeByHost() (map[string]int, error) {
+	sizes := make(map[string]int)
+	err := s.db.Load().View(func(tx *bbolt.Tx) error {
+		qb := tx.Bucket(bucketQueue)
+		return qb.ForEach(func(k, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			sizes[string(k)] = qb.Bucket(k).Stats().KeyN
+			return nil
+		})
+	})
+	return sizes, err
+}
+
 func u64ToBytes(n uint64) []byte {
 	return []byte{
 		byte(0xff & n),